@@ -52,13 +52,73 @@ var (
 type BSeg struct {
 	dict    map[string]int
 	unigram map[string]int
+
+	workers   int
+	blockSize int
+
+	idx *ngramIndex
+
+	seed      int64
+	maxMWELen int
+
+	// This model's own hyperparameters, initialized from the package flags
+	// at construction time so Load can restore them per-instance.
+	alpha    float64
+	segProb  float64
+	annIters int
+	iters    int
+}
+
+// Option configures a BSeg at construction time.
+type Option func(*BSeg)
+
+// WithWorkers sets the number of goroutines Sample uses to process a sweep
+// in parallel. Workers==1 (the default) keeps the original single-threaded,
+// deterministic sampling order.
+func WithWorkers(n int) Option {
+	return func(s *BSeg) { s.workers = n }
+}
+
+// WithBlockSize bounds how many tokens a single parallel-sample worker
+// processes before a synthetic barrier is inserted, in addition to the
+// barriers FIXSEG anchors already impose. Only relevant when WithWorkers
+// specifies more than one worker.
+func WithBlockSize(n int) Option {
+	return func(s *BSeg) { s.blockSize = n }
+}
+
+// WithSeed sets the math/rand seed Sample's draws use. Load uses this to
+// restore the seed a saved model was trained with, so that resuming Sample
+// on a loaded model is reproducible.
+func WithSeed(seed int64) Option {
+	return func(s *BSeg) { s.seed = seed }
+}
+
+// WithMaxMWELen bounds the span length Segment/SegmentN will consider when
+// decoding a trained model against new text. Defaults to defaultMaxMWELen.
+func WithMaxMWELen(n int) Option {
+	return func(s *BSeg) { s.maxMWELen = n }
 }
 
-func NewBSeg() *BSeg {
+func NewBSeg(opts ...Option) *BSeg {
 	s := new(BSeg)
 	s.dict = make(map[string]int)
 	s.unigram = make(map[string]int)
-	rand.Seed(1234)
+	s.workers = 1
+	s.seed = 1234
+	s.alpha = *alpha
+	s.segProb = *seg_prob
+	s.annIters = *ann_iters
+	s.iters = *iters
+
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.workers < 1 {
+		s.workers = 1
+	}
+
+	rand.Seed(s.seed)
 	return s
 }
 
@@ -125,13 +185,29 @@ func (s *BSeg) IncrDict(word string) {
 	s.dict[word]++
 }
 
+// LogProbMWE computes the log-probability of tokens[i1:i2] forming a single
+// MWE under the unigram base distribution plus the seg_prob prior on its
+// internal boundaries. If BuildIndex has been called, it is served from the
+// integer-encoded, math.Log-cached index (see index.go) instead of doing a
+// fresh map[string]int lookup and math.Log call per token; the two paths
+// agree up to floating-point rounding, since the index computes
+// log(count+1)-log(N) where the slow path computes log((count+1)/N).
 func (s *BSeg) LogProbMWE(tokens []string, i1, i2 int) float64 {
-	logProb := float64(0.0)
 	N := len(s.dict) + len(s.unigram)
+	if s.idx != nil {
+		if logProb, ok := s.idx.logProbMWE(tokens, i1, i2, N, s.segProb); ok {
+			return logProb
+		}
+	}
+	return s.logProbMWESlow(tokens, i1, i2, N)
+}
+
+func (s *BSeg) logProbMWESlow(tokens []string, i1, i2, N int) float64 {
+	logProb := float64(0.0)
 	for k := i1; k < i2; k++ {
 		logProb += math.Log(float64(s.unigram[tokens[k]]+1.0) / float64(N))
 	}
-	logProb += math.Log(*seg_prob) + float64(i2-i1-1)*math.Log(1-*seg_prob)
+	logProb += math.Log(s.segProb) + float64(i2-i1-1)*math.Log(1-s.segProb)
 	return logProb
 }
 
@@ -183,22 +259,36 @@ func (s *BSeg) ProcessText(tokens []string, segments []uint8) {
 		s.IncrDict(strings.Join(tokens[iStart:iEnd], " "))
 	}
 
-	for i := 0; i < (*ann_iters + *iters); i++ {
-		temp := float64(i+1) / float64(*ann_iters)
+	s.BuildIndex(tokens)
+
+	for i := 0; i < (s.annIters + s.iters); i++ {
+		temp := float64(i+1) / float64(s.annIters)
 		if temp > 1 {
 			temp = 1
 		}
 		if *print_log {
 			log.Printf("iter %d  Temp=%.2f", i, temp)
 		}
-		s.Sample(*alpha, temp, tokens, segments)
+		s.Sample(s.alpha, temp, tokens, segments)
 		if *print_log && i/10*10 == i {
 			s.PrintDictStats()
 		}
 	}
 }
 
+// Sample runs one Gibbs sweep over tokens, updating segments and dict in
+// place. When the BSeg was built with WithWorkers(n) for n>1, the sweep is
+// partitioned across goroutines (see sample_parallel.go); otherwise it runs
+// the original single-threaded, deterministic update order.
 func (s *BSeg) Sample(alpha, temperature float64, tokens []string, segments []uint8) {
+	if s.workers > 1 {
+		s.sampleParallel(alpha, temperature, tokens, segments)
+		return
+	}
+	s.sampleSequential(alpha, temperature, tokens, segments)
+}
+
+func (s *BSeg) sampleSequential(alpha, temperature float64, tokens []string, segments []uint8) {
 	N := len(s.dict)
 	invNPlusAlpha := 1.0 / (float64(N) + alpha)
 
@@ -0,0 +1,116 @@
+package bseg
+
+import (
+	"encoding/binary"
+	"index/suffixarray"
+	"math"
+	"sync"
+)
+
+// ngramIndex speeds up LogProbMWE on the hot sampling path by encoding
+// tokens as int32 ids instead of repeatedly hashing the same short strings.
+type ngramIndex struct {
+	tokenID map[string]int32
+	sa      *suffixarray.Index
+	data    []byte
+
+	unigramCount map[int32]int
+
+	logCacheMu sync.Mutex
+	logCache   map[int]float64
+}
+
+// BuildIndex builds the n-gram index LogProbMWE consults. ProcessText calls
+// this automatically; it is exported so a model used only with
+// Segment/SegmentN can have one built too, since those never build one.
+func (s *BSeg) BuildIndex(tokens []string) {
+	idx := &ngramIndex{
+		tokenID:      make(map[string]int32, len(s.unigram)),
+		unigramCount: make(map[int32]int, len(s.unigram)),
+		logCache:     make(map[int]float64),
+	}
+
+	ids := make([]int32, len(tokens))
+	var next int32
+	for i, t := range tokens {
+		id, ok := idx.tokenID[t]
+		if !ok {
+			id = next
+			idx.tokenID[t] = id
+			idx.unigramCount[id] = s.unigram[t]
+			next++
+		}
+		ids[i] = id
+	}
+
+	data := make([]byte, len(ids)*4)
+	for i, id := range ids {
+		binary.BigEndian.PutUint32(data[i*4:], uint32(id))
+	}
+	idx.data = data
+	idx.sa = suffixarray.New(data)
+
+	s.idx = idx
+}
+
+// logOf returns math.Log(n), cached by the integer argument. Guarded by a
+// mutex since sampleBlock workers call it concurrently.
+func (idx *ngramIndex) logOf(n int) float64 {
+	idx.logCacheMu.Lock()
+	defer idx.logCacheMu.Unlock()
+
+	if lp, ok := idx.logCache[n]; ok {
+		return lp
+	}
+	lp := math.Log(float64(n))
+	idx.logCache[n] = lp
+	return lp
+}
+
+// logProbMWE is the index-backed fast path for BSeg.LogProbMWE; ok is false
+// if a token in the span was never seen while building the index.
+func (idx *ngramIndex) logProbMWE(tokens []string, i1, i2, N int, segProb float64) (float64, bool) {
+	logN := idx.logOf(N)
+	logProb := 0.0
+	for k := i1; k < i2; k++ {
+		id, ok := idx.tokenID[tokens[k]]
+		if !ok {
+			return 0, false
+		}
+		logProb += idx.logOf(idx.unigramCount[id]+1) - logN
+	}
+	logProb += math.Log(segProb) + float64(i2-i1-1)*math.Log(1-segProb)
+	return logProb, true
+}
+
+// NgramCount returns how many times phrase occurs in the indexed token
+// stream, via a suffix-array lookup over the packed id stream; ok is false
+// if BuildIndex hasn't run or a token of phrase was never indexed. A phrase
+// whose tokens are all indexed but never occur in that order yields 0, true.
+func (s *BSeg) NgramCount(phrase []string) (count int, ok bool) {
+	if s.idx == nil {
+		return 0, false
+	}
+
+	ids := make([]int32, len(phrase))
+	for i, t := range phrase {
+		id, found := s.idx.tokenID[t]
+		if !found {
+			return 0, false
+		}
+		ids[i] = id
+	}
+
+	pattern := make([]byte, len(ids)*4)
+	for i, id := range ids {
+		binary.BigEndian.PutUint32(pattern[i*4:], uint32(id))
+	}
+
+	occurrences := 0
+	for _, off := range s.idx.sa.Lookup(pattern, -1) {
+		if off%4 == 0 {
+			occurrences++
+		}
+	}
+	return occurrences, true
+}
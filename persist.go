@@ -0,0 +1,149 @@
+package bseg
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	saveMagic   = "BSEG"
+	saveVersion = 1
+)
+
+// modelState is the gob-encoded payload Save/Load round-trip.
+type modelState struct {
+	Dict    map[string]int
+	Unigram map[string]int
+
+	Alpha    float64
+	SegProb  float64
+	AnnIters int
+	Iters    int
+	Seed     int64
+}
+
+// Save writes s to w as a magic header, a version, then a gob-encoded
+// modelState. Load reverses this.
+func (s *BSeg) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(saveMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(bw, binary.BigEndian, uint32(saveVersion)); err != nil {
+		return err
+	}
+
+	state := modelState{
+		Dict:     s.dict,
+		Unigram:  s.unigram,
+		Alpha:    s.alpha,
+		SegProb:  s.segProb,
+		AnnIters: s.annIters,
+		Iters:    s.iters,
+		Seed:     s.seed,
+	}
+	if err := gob.NewEncoder(bw).Encode(&state); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// SaveGzip is Save wrapped in a gzip writer.
+func (s *BSeg) SaveGzip(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	if err := s.Save(gw); err != nil {
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
+// Load reads a BSeg previously written by Save.
+func Load(r io.Reader) (*BSeg, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(saveMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != saveMagic {
+		return nil, fmt.Errorf("bseg: not a BSeg save file (bad magic %q)", magic)
+	}
+
+	var version uint32
+	if err := binary.Read(br, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != saveVersion {
+		return nil, fmt.Errorf("bseg: unsupported save version %d", version)
+	}
+
+	var state modelState
+	if err := gob.NewDecoder(br).Decode(&state); err != nil {
+		return nil, err
+	}
+
+	s := NewBSeg(WithSeed(state.Seed))
+	s.dict = state.Dict
+	s.unigram = state.Unigram
+	s.alpha = state.Alpha
+	s.segProb = state.SegProb
+	s.annIters = state.AnnIters
+	s.iters = state.Iters
+	return s, nil
+}
+
+// LoadGzip reads a stream written by SaveGzip.
+func LoadGzip(r io.Reader) (*BSeg, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	return Load(gr)
+}
+
+// SaveFormat writes s to w in the given format: "gob" (Save), "jsonl" (one
+// JSON Token per GetDict entry), or "text" (DumpDict's "name count" format).
+// "jsonl" and "text" only hold the entries that pass the min_token_count and
+// min_token_length filters. Only "gob" round-trips via Load.
+func (s *BSeg) SaveFormat(w io.Writer, format string) error {
+	switch format {
+	case "gob":
+		return s.Save(w)
+	case "jsonl":
+		return s.saveJSONL(w)
+	case "text":
+		return s.saveText(w)
+	default:
+		return fmt.Errorf("bseg: unknown save format %q", format)
+	}
+}
+
+func (s *BSeg) saveText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	ts := s.GetDict()
+	for i := range ts {
+		if _, err := fmt.Fprintf(bw, "%s %d\n", ts[i].Name, ts[i].Count); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (s *BSeg) saveJSONL(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	ts := s.GetDict()
+	for i := range ts {
+		if err := enc.Encode(ts[i]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,48 @@
+package bseg
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	*print_log = false
+
+	tokens := strings.Fields("the quick brown fox the quick brown fox the quick brown fox")
+	segments := make([]uint8, len(tokens))
+	for i := range segments {
+		segments[i] = NOSEG
+	}
+
+	s := NewBSeg(WithSeed(42))
+	s.annIters = 1
+	s.iters = 1
+	s.ProcessText(tokens, segments)
+
+	var buf bytes.Buffer
+	if err := s.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := append([]uint8(nil), segments...)
+	got := append([]uint8(nil), segments...)
+
+	rand.Seed(42)
+	s.Sample(s.alpha, 1, tokens, want)
+
+	rand.Seed(42)
+	loaded.Sample(loaded.alpha, 1, tokens, got)
+
+	for i := range want {
+		if want[i] != got[i] {
+			t.Fatalf("segments diverged at index %d: want %d got %d", i, want[i], got[i])
+		}
+	}
+}
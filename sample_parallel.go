@@ -0,0 +1,175 @@
+package bseg
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// blockBounds splits tokens into disjoint [start, end) ranges, independent
+// of the worker count: a new block starts after every FIXSEG anchor and,
+// within a FIXSEG-delimited span, after every blockSize tokens.
+func (s *BSeg) blockBounds(tokens []string, segments []uint8) [][2]int {
+	blockSize := s.blockSize
+	if blockSize <= 0 {
+		blockSize = len(tokens)
+	}
+
+	bounds := make([][2]int, 0)
+	start := 0
+	for i := 0; i < len(tokens); i++ {
+		if segments[i] == FIXSEG || i-start+1 >= blockSize {
+			bounds = append(bounds, [2]int{start, i + 1})
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		bounds = append(bounds, [2]int{start, len(tokens)})
+	}
+	return bounds
+}
+
+// sampleParallel runs one Gibbs sweep with the sweep partitioned into
+// blockBounds blocks processed concurrently. dict is read-only for the
+// sweep; each block accumulates its own delta map, merged into s.dict once
+// every worker has finished.
+func (s *BSeg) sampleParallel(alpha, temperature float64, tokens []string, segments []uint8) {
+	bounds := s.blockBounds(tokens, segments)
+	deltas := make([]map[string]int, len(bounds))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.workers)
+
+	for i, b := range bounds {
+		i, b := i, b
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			deltas[i] = s.sampleBlock(alpha, temperature, tokens, segments, b[0], b[1])
+		}()
+	}
+	wg.Wait()
+
+	for _, delta := range deltas {
+		for word, d := range delta {
+			v := s.dict[word] + d
+			if v > 0 {
+				s.dict[word] = v
+			} else {
+				delete(s.dict, word)
+			}
+		}
+	}
+}
+
+// sampleBlock is sampleSequential's inner loop restricted to [start, end);
+// start clamps the iL context search and contextLimit, not loopBound, clamps
+// the iR one, while loopBound lets i reach end-1 so that boundary still gets
+// sampled instead of falling into the gap between two blocks.
+func (s *BSeg) sampleBlock(alpha, temperature float64, tokens []string, segments []uint8, start, end int) map[string]int {
+	delta := make(map[string]int)
+	findInDict := func(word string) int {
+		return s.dict[word] + delta[word]
+	}
+	incrDict := func(word string) { delta[word]++ }
+	decrDict := func(word string) { delta[word]-- }
+
+	N := len(s.dict)
+	invNPlusAlpha := 1.0 / (float64(N) + alpha)
+
+	if end > len(tokens) {
+		end = len(tokens)
+	}
+	contextLimit := end - 1
+	loopBound := end
+	if loopBound > len(tokens)-1 {
+		loopBound = len(tokens) - 1
+	}
+
+	var mweL, mweR, mweLR string
+	var i, iL, iR int
+	var numL, numR, numLR int
+
+	for i = start; i < loopBound; i++ {
+		if segments[i] == FIXSEG {
+			continue
+		}
+
+		i1 := i + 1
+
+		iL = i - 1
+		for iL >= start && segments[iL] == NOSEG {
+			iL--
+		}
+		iL++
+		if i1-iL > 1 {
+			mweL = strings.Join(tokens[iL:i1], " ")
+		} else {
+			mweL = tokens[i]
+		}
+
+		iR = i + 1
+		for iR < contextLimit && segments[iR] == NOSEG {
+			iR++
+		}
+		iR++
+		if iR-i1 > 1 {
+			mweR = strings.Join(tokens[i1:iR], " ")
+		} else {
+			mweR = tokens[i1]
+		}
+		mweLR = mweL + " " + mweR
+
+		if segments[i] == SEG {
+			numL = findInDict(mweL)
+			numR = findInDict(mweR)
+			numLR = findInDict(mweLR)
+			numL--
+			numR--
+		} else {
+			numL = findInDict(mweL)
+			numR = findInDict(mweR)
+			numLR = findInDict(mweLR)
+			numLR--
+		}
+
+		var sumProb float64
+		logProbL := s.LogProbMWE(tokens, iL, i1)
+		logProbR := s.LogProbMWE(tokens, i1, iR)
+		logProbLR := logProbL + logProbR
+
+		prob0 := (float64(numLR) + alpha*math.Exp(logProbLR)) * invNPlusAlpha
+		prob1L := (float64(numL) + alpha*math.Exp(logProbL)) * invNPlusAlpha
+		prob1R := (float64(numR) + alpha*math.Exp(logProbR)) * invNPlusAlpha
+		prob1 := prob1L * prob1R
+
+		if temperature < 0.999 {
+			sumProb = prob0 + prob1
+			prob0 /= sumProb
+			prob1 /= sumProb
+			prob0 = math.Pow(prob0, temperature)
+			prob1 = math.Pow(prob1, temperature)
+		}
+
+		sumProb = prob0 + prob1
+		prob0 /= sumProb
+		insertSeg := rand.Float64() > prob0
+
+		if segments[i] == NOSEG && insertSeg {
+			segments[i] = SEG
+			decrDict(mweLR)
+			incrDict(mweL)
+			incrDict(mweR)
+		} else if segments[i] == SEG && !insertSeg {
+			segments[i] = NOSEG
+			decrDict(mweL)
+			decrDict(mweR)
+			incrDict(mweLR)
+		}
+	}
+
+	return delta
+}
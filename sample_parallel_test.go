@@ -0,0 +1,30 @@
+package bseg
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParallelSampleNoRace exercises the WithWorkers(n>1) path; run with
+// `go test -race` to catch concurrent access to shared state such as
+// ngramIndex.logCache.
+func TestParallelSampleNoRace(t *testing.T) {
+	*print_log = false
+
+	tokens := strings.Fields(strings.Repeat("the quick brown fox jumps over the lazy dog ", 20))
+	segments := make([]uint8, len(tokens))
+	for i := range segments {
+		segments[i] = NOSEG
+	}
+
+	s := NewBSeg(WithWorkers(4), WithBlockSize(8))
+	s.annIters = 3
+	s.iters = 3
+	s.ProcessText(tokens, segments)
+
+	for _, seg := range segments {
+		if seg != NOSEG && seg != SEG && seg != FIXSEG {
+			t.Fatalf("invalid segment value %d", seg)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package bseg
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// defaultMaxMWELen bounds the MWE span length Segment/SegmentN consider when
+// WithMaxMWELen was not given.
+const defaultMaxMWELen = 8
+
+func (s *BSeg) maxSpanLen() int {
+	if s.maxMWELen > 0 {
+		return s.maxMWELen
+	}
+	return defaultMaxMWELen
+}
+
+// logSpanScore is the same DP posterior predictive Sample draws boundary
+// decisions from - (dictCount(span) + alpha*exp(LogProbMWE(span))) /
+// (len(dict) + alpha) - rather than the raw unigram base measure LogProbMWE
+// alone. Without this, decoding never actually consults the trained dict:
+// LogProbMWE only reads s.unigram, which ProcessText fills once before any
+// sampling and never changes, and len(s.dict), which shifts every token's
+// score by the same amount.
+func (s *BSeg) logSpanScore(tokens []string, i1, i2 int) float64 {
+	count := float64(s.FindInDict(strings.Join(tokens[i1:i2], " ")))
+	base := math.Exp(s.LogProbMWE(tokens, i1, i2))
+	return math.Log((count + s.alpha*base) / (float64(len(s.dict)) + s.alpha))
+}
+
+// Segment performs MAP decoding of boundary decisions via Viterbi over the
+// lattice of MWE spans up to maxMWELen long, scored by logSpanScore (the
+// same dict-backed posterior Sample draws from). Unlike ProcessText/Sample,
+// it never mutates dict or unigram.
+func (s *BSeg) Segment(tokens []string) []uint8 {
+	n := len(tokens)
+	segments := make([]uint8, n)
+	for i := range segments {
+		segments[i] = NOSEG
+	}
+	if n == 0 {
+		return segments
+	}
+
+	maxLen := s.maxSpanLen()
+
+	// best[i] is the highest log-prob segmentation of tokens[0:i]; back[i]
+	// is the length of the last span that achieves it.
+	best := make([]float64, n+1)
+	back := make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = math.Inf(-1)
+	}
+
+	for i := 1; i <= n; i++ {
+		maxSpan := maxLen
+		if maxSpan > i {
+			maxSpan = i
+		}
+		for span := 1; span <= maxSpan; span++ {
+			j := i - span
+			if math.IsInf(best[j], -1) {
+				continue
+			}
+			lp := best[j] + s.logSpanScore(tokens, j, i)
+			if lp > best[i] {
+				best[i] = lp
+				back[i] = span
+			}
+		}
+	}
+
+	for i := n; i > 0; {
+		span := back[i]
+		j := i - span
+		if j > 0 {
+			segments[j-1] = SEG
+		}
+		i = j
+	}
+
+	return segments
+}
+
+// SegmentBest is Segment decoded into the token groups it implies.
+func (s *BSeg) SegmentBest(tokens []string) [][]string {
+	return groupBySegments(tokens, s.Segment(tokens))
+}
+
+// segPath is one partial segmentation kept by SegmentN's beam search.
+type segPath struct {
+	logProb float64
+	spans   []int
+}
+
+// SegmentN returns up to nBest segmentations of tokens, ranked by total
+// log-probability, via a beam search over the same lattice Segment decodes
+// with Viterbi.
+func (s *BSeg) SegmentN(tokens []string, nBest int) [][][]string {
+	n := len(tokens)
+	if n == 0 || nBest <= 0 {
+		return nil
+	}
+
+	maxLen := s.maxSpanLen()
+
+	beams := make([][]segPath, n+1)
+	beams[0] = []segPath{{}}
+
+	for i := 1; i <= n; i++ {
+		maxSpan := maxLen
+		if maxSpan > i {
+			maxSpan = i
+		}
+
+		var cands []segPath
+		for span := 1; span <= maxSpan; span++ {
+			j := i - span
+			lp := s.logSpanScore(tokens, j, i)
+			for _, p := range beams[j] {
+				spans := make([]int, len(p.spans)+1)
+				copy(spans, p.spans)
+				spans[len(p.spans)] = span
+				cands = append(cands, segPath{logProb: p.logProb + lp, spans: spans})
+			}
+		}
+
+		sort.Slice(cands, func(a, b int) bool { return cands[a].logProb > cands[b].logProb })
+		if len(cands) > nBest {
+			cands = cands[:nBest]
+		}
+		beams[i] = cands
+	}
+
+	out := make([][][]string, 0, len(beams[n]))
+	for _, p := range beams[n] {
+		segments := make([]uint8, n)
+		for i := range segments {
+			segments[i] = NOSEG
+		}
+		pos := 0
+		for _, span := range p.spans {
+			pos += span
+			if pos < n {
+				segments[pos-1] = SEG
+			}
+		}
+		out = append(out, groupBySegments(tokens, segments))
+	}
+	return out
+}
+
+// groupBySegments splits tokens into the groups segments' SEG/FIXSEG
+// boundaries imply.
+func groupBySegments(tokens []string, segments []uint8) [][]string {
+	groups := make([][]string, 0)
+	start := 0
+	for i := 0; i < len(tokens); i++ {
+		if i == len(tokens)-1 || segments[i] != NOSEG {
+			group := make([]string, i-start+1)
+			copy(group, tokens[start:i+1])
+			groups = append(groups, group)
+			start = i + 1
+		}
+	}
+	return groups
+}
@@ -0,0 +1,37 @@
+package bseg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSegmentUsesTrainedDict checks that Segment's output actually depends on
+// the trained dict, not just the unigram base measure: an untrained model
+// (empty dict, same unigram counts) should segment a corpus with an obvious
+// repeated MWE differently from a model trained on it.
+func TestSegmentUsesTrainedDict(t *testing.T) {
+	*print_log = false
+
+	tokens := strings.Fields(strings.Repeat("new york city is big new york city is big ", 15))
+	segments := make([]uint8, len(tokens))
+	for i := range segments {
+		segments[i] = NOSEG
+	}
+
+	trained := NewBSeg(WithSeed(7))
+	trained.annIters = 50
+	trained.iters = 50
+	trained.ProcessText(tokens, segments)
+
+	untrained := NewBSeg(WithSeed(7))
+	untrained.unigram = trained.unigram
+	untrained.BuildIndex(tokens)
+
+	trainedOut := trained.SegmentBest(tokens)
+	untrainedOut := untrained.SegmentBest(tokens)
+
+	if reflect.DeepEqual(trainedOut, untrainedOut) {
+		t.Fatalf("trained and untrained Segment output are identical; dict isn't influencing decoding")
+	}
+}
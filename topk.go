@@ -0,0 +1,103 @@
+package bseg
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// scoredToken pairs a Token with the score it was ranked by, since the score
+// isn't always Token.Count.
+type scoredToken struct {
+	tok   Token
+	score float64
+}
+
+// tokenMinHeap is a container/heap min-heap over scoredToken, kept bounded
+// to size n so TopKByScore avoids sorting the whole dict.
+type tokenMinHeap []scoredToken
+
+func (h tokenMinHeap) Len() int            { return len(h) }
+func (h tokenMinHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h tokenMinHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *tokenMinHeap) Push(x interface{}) { *h = append(*h, x.(scoredToken)) }
+func (h *tokenMinHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// TopK returns the n most frequent multi-word expressions in the dict.
+func (s *BSeg) TopK(n int) Tokens {
+	return s.TopKByScore(n, func(name string, count int) float64 {
+		return float64(count)
+	})
+}
+
+// TopKByScore is like TopK but ranks entries by a caller-supplied score
+// instead of raw count, e.g. PMI or a log-probability from LogProbMWE.
+func (s *BSeg) TopKByScore(n int, score func(name string, count int) float64) Tokens {
+	if n <= 0 {
+		return Tokens{}
+	}
+
+	h := &tokenMinHeap{}
+	heap.Init(h)
+
+	for k, v := range s.dict {
+		if v < *min_token_count {
+			continue
+		}
+		ws := strings.Split(k, " ")
+		if len(ws) < *min_token_length {
+			continue
+		}
+		name := strings.Join(ws, "")
+		st := scoredToken{tok: Token{Name: name, Count: v}, score: score(name, v)}
+
+		if h.Len() < n {
+			heap.Push(h, st)
+		} else if h.Len() > 0 && st.score > (*h)[0].score {
+			heap.Pop(h)
+			heap.Push(h, st)
+		}
+	}
+
+	popped := make([]scoredToken, 0, h.Len())
+	for h.Len() > 0 {
+		popped = append(popped, heap.Pop(h).(scoredToken))
+	}
+	sort.Slice(popped, func(i, j int) bool { return popped[i].score > popped[j].score })
+
+	ts := make(Tokens, len(popped))
+	for i, st := range popped {
+		ts[i] = st.tok
+	}
+	return ts
+}
+
+// TopKTo writes the top-n most frequent MWEs to w in the same "name count"
+// format DumpDict uses.
+func (s *BSeg) TopKTo(w io.Writer, n int) error {
+	return s.TopKByScoreTo(w, n, func(name string, count int) float64 {
+		return float64(count)
+	})
+}
+
+// TopKByScoreTo is the io.Writer variant of TopKByScore.
+func (s *BSeg) TopKByScoreTo(w io.Writer, n int, score func(name string, count int) float64) error {
+	ts := s.TopKByScore(n, score)
+
+	bw := bufio.NewWriter(w)
+	for i := 0; i < len(ts); i++ {
+		if _, err := fmt.Fprintf(bw, "%s %d\n", ts[i].Name, ts[i].Count); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}